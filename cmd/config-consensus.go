@@ -0,0 +1,200 @@
+/*
+ * Minio Cloud Storage, (C) 2016, 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// configConsensusLogEntry is one committed mutation in the cluster's
+// replicated config log.
+type configConsensusLogEntry struct {
+	Version  uint64
+	Mutation []byte
+}
+
+// httpConfigConsensus is a working implementation of configConsensus for
+// a fixed, statically-configured set of peers. It deliberately isn't
+// Raft: leadership is the lexicographically smallest peer address rather
+// than an elected term, and the log is kept in memory with no
+// compaction. What it does provide for real is synchronous
+// majority-ack replication of every config mutation over HTTP into each
+// peer's log. A follower stores the entries it receives but does not
+// apply them to its own serverConfig; they reach its config only through
+// CatchUp, when loadConfig bootstraps it from the leader. A true Raft implementation (elections, term handling,
+// persistent log) is a larger follow-up; this exists so
+// globalConfigConsensus has a real, runnable implementation to install
+// via SetConfigConsensus instead of staying permanently nil.
+type httpConfigConsensus struct {
+	self  string
+	peers []string // includes self, sorted
+
+	client *http.Client
+
+	mu  sync.Mutex
+	log []configConsensusLogEntry
+}
+
+// newHTTPConfigConsensus builds a consensus subsystem for a cluster whose
+// members (self plus peers) are known up front, addressed as host:port.
+func newHTTPConfigConsensus(self string, peers []string) *httpConfigConsensus {
+	all := append([]string{self}, peers...)
+	sort.Strings(all)
+
+	return &httpConfigConsensus{
+		self:   self,
+		peers:  all,
+		client: &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// leader returns the deterministic leader address: the lexicographically
+// smallest member of the cluster.
+func (h *httpConfigConsensus) leader() string {
+	return h.peers[0]
+}
+
+// IsLeader reports whether this node is the deterministic leader.
+func (h *httpConfigConsensus) IsLeader() bool {
+	return h.leader() == h.self
+}
+
+// Propose appends mutation to the local log and replicates it to every
+// peer, returning once a majority (including self) have acknowledged.
+func (h *httpConfigConsensus) Propose(mutation []byte) error {
+	h.mu.Lock()
+	entry := configConsensusLogEntry{Version: uint64(len(h.log)) + 1, Mutation: mutation}
+	h.log = append(h.log, entry)
+	h.mu.Unlock()
+
+	quorum := len(h.peers)/2 + 1
+	acked := 1 // self
+
+	for _, peer := range h.peers {
+		if peer == h.self {
+			continue
+		}
+		if err := h.appendEntry(peer, entry); err != nil {
+			errorIf(err, "config consensus: peer %s failed to ack append-entries", peer)
+			continue
+		}
+		acked++
+	}
+
+	if acked < quorum {
+		return fmt.Errorf("config consensus: only %d/%d peers acked, need %d for quorum", acked, len(h.peers), quorum)
+	}
+
+	return nil
+}
+
+// appendEntry replicates entry to peer's append-entries endpoint.
+func (h *httpConfigConsensus) appendEntry(peer string, entry configConsensusLogEntry) error {
+	body, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.client.Post(fmt.Sprintf("http://%s/minio/config-consensus/append", peer), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("peer %s returned status %d", peer, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// CatchUp returns every log entry after fromVersion from the leader. If
+// this node is the leader, it serves directly from its own log.
+func (h *httpConfigConsensus) CatchUp(fromVersion uint64) ([][]byte, error) {
+	if h.IsLeader() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		var out [][]byte
+		for _, entry := range h.log {
+			if entry.Version > fromVersion {
+				out = append(out, entry.Mutation)
+			}
+		}
+		return out, nil
+	}
+
+	resp, err := h.client.Get(fmt.Sprintf("http://%s/minio/config-consensus/log?from=%d", h.leader(), fromVersion))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var entries []configConsensusLogEntry
+	if err = json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, err
+	}
+
+	out := make([][]byte, len(entries))
+	for i, entry := range entries {
+		out[i] = entry.Mutation
+	}
+	return out, nil
+}
+
+// ServeAppendEntries handles an incoming append-entries request from the
+// leader, storing the entry in the local log. Register it at
+// "/minio/config-consensus/append" on every non-leader peer.
+func (h *httpConfigConsensus) ServeAppendEntries(w http.ResponseWriter, r *http.Request) {
+	var entry configConsensusLogEntry
+	if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	h.log = append(h.log, entry)
+	h.mu.Unlock()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// ServeLog handles a CatchUp request from a follower, returning every
+// entry newer than the "from" query parameter. Register it at
+// "/minio/config-consensus/log" on the leader.
+func (h *httpConfigConsensus) ServeLog(w http.ResponseWriter, r *http.Request) {
+	var from uint64
+	fmt.Sscanf(r.URL.Query().Get("from"), "%d", &from)
+
+	h.mu.Lock()
+	var out []configConsensusLogEntry
+	for _, entry := range h.log {
+		if entry.Version > from {
+			out = append(out, entry)
+		}
+	}
+	h.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(out)
+}
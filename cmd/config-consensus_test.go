@@ -0,0 +1,96 @@
+/*
+ * Minio Cloud Storage, (C) 2016, 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestPeer starts an httptest server backed by its own httpConfigConsensus
+// and returns its address (host:port, as httpConfigConsensus addresses peers)
+// along with a closer.
+func newTestPeer(self string, peers []string) (addr string, closer func()) {
+	h := newHTTPConfigConsensus(self, peers)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/minio/config-consensus/append", h.ServeAppendEntries)
+	mux.HandleFunc("/minio/config-consensus/log", h.ServeLog)
+
+	srv := httptest.NewServer(mux)
+	return strings.TrimPrefix(srv.URL, "http://"), srv.Close
+}
+
+func TestHTTPConfigConsensusLeaderIsDeterministic(t *testing.T) {
+	h := newHTTPConfigConsensus("b", []string{"a", "c"})
+	if got := h.leader(); got != "a" {
+		t.Errorf("leader() = %q, want %q", got, "a")
+	}
+	if h.IsLeader() {
+		t.Error("IsLeader() = true for a non-leader node")
+	}
+
+	h = newHTTPConfigConsensus("a", []string{"b", "c"})
+	if !h.IsLeader() {
+		t.Error("IsLeader() = false for the lexicographically smallest node")
+	}
+}
+
+func TestHTTPConfigConsensusProposeQuorum(t *testing.T) {
+	// Three-node cluster: self plus two real HTTP peers that can actually
+	// append entries, so quorum (2 of 3, including self) is reachable.
+	addr2, close2 := newTestPeer("placeholder2", nil)
+	defer close2()
+	addr3, close3 := newTestPeer("placeholder3", nil)
+	defer close3()
+
+	h := newHTTPConfigConsensus("self:0", []string{addr2, addr3})
+
+	if err := h.Propose([]byte(`{"region":"us-west-2"}`)); err != nil {
+		t.Fatalf("Propose() with 2/3 peers reachable = %v, want success", err)
+	}
+}
+
+func TestHTTPConfigConsensusProposeFailsWithoutQuorum(t *testing.T) {
+	// Three-node cluster where both other peers are unreachable: only
+	// self acks, which is below the quorum of 2.
+	h := newHTTPConfigConsensus("self:0", []string{"127.0.0.1:1", "127.0.0.1:2"})
+
+	if err := h.Propose([]byte(`{"region":"us-west-2"}`)); err == nil {
+		t.Error("Propose() with no reachable peers succeeded, want a quorum error")
+	}
+}
+
+func TestHTTPConfigConsensusCatchUpServesLocalLogWhenLeader(t *testing.T) {
+	h := newHTTPConfigConsensus("a", []string{"b", "c"})
+
+	h.mu.Lock()
+	h.log = append(h.log,
+		configConsensusLogEntry{Version: 1, Mutation: []byte(`{"region":"us-east-1"}`)},
+		configConsensusLogEntry{Version: 2, Mutation: []byte(`{"region":"us-west-2"}`)},
+	)
+	h.mu.Unlock()
+
+	entries, err := h.CatchUp(1)
+	if err != nil {
+		t.Fatalf("CatchUp() error = %v", err)
+	}
+	if len(entries) != 1 || string(entries[0]) != `{"region":"us-west-2"}` {
+		t.Errorf("CatchUp(1) = %v, want only the entry newer than version 1", entries)
+	}
+}
@@ -0,0 +1,240 @@
+/*
+ * Minio Cloud Storage, (C) 2016, 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/minio/minio/pkg/auth"
+)
+
+func TestBucketAccessPolicyAllows(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	testCases := []struct {
+		name     string
+		policy   BucketAccessPolicy
+		action   string
+		resource string
+		sourceIP string
+		want     bool
+	}{
+		{
+			name:   "empty policy denies everything",
+			policy: BucketAccessPolicy{},
+			action: "s3:GetObject",
+			want:   false,
+		},
+		{
+			name:   "action not listed is denied",
+			policy: BucketAccessPolicy{AllowedActions: []string{"s3:PutObject"}},
+			action: "s3:GetObject",
+			want:   false,
+		},
+		{
+			name:   "listed action with no other restrictions is allowed",
+			policy: BucketAccessPolicy{AllowedActions: []string{"s3:GetObject"}},
+			action: "s3:GetObject",
+			want:   true,
+		},
+		{
+			name: "resource must match one of the prefixes",
+			policy: BucketAccessPolicy{
+				AllowedActions:   []string{"s3:GetObject"},
+				ResourcePrefixes: []string{"logs/"},
+			},
+			action:   "s3:GetObject",
+			resource: "images/cat.png",
+			want:     false,
+		},
+		{
+			name: "resource matching a prefix is allowed",
+			policy: BucketAccessPolicy{
+				AllowedActions:   []string{"s3:GetObject"},
+				ResourcePrefixes: []string{"logs/"},
+			},
+			action:   "s3:GetObject",
+			resource: "logs/2017-01-01.log",
+			want:     true,
+		},
+		{
+			name: "source IP outside the allowed CIDR is denied",
+			policy: BucketAccessPolicy{
+				AllowedActions: []string{"s3:GetObject"},
+				AllowedCIDRs:   []string{"10.0.0.0/8"},
+			},
+			action:   "s3:GetObject",
+			sourceIP: "192.168.1.1",
+			want:     false,
+		},
+		{
+			name: "source IP inside the allowed CIDR is allowed",
+			policy: BucketAccessPolicy{
+				AllowedActions: []string{"s3:GetObject"},
+				AllowedCIDRs:   []string{"10.0.0.0/8"},
+			},
+			action:   "s3:GetObject",
+			sourceIP: "10.1.2.3",
+			want:     true,
+		},
+		{
+			name: "missing source IP skips the CIDR check",
+			policy: BucketAccessPolicy{
+				AllowedActions: []string{"s3:GetObject"},
+				AllowedCIDRs:   []string{"10.0.0.0/8"},
+			},
+			action: "s3:GetObject",
+			want:   true,
+		},
+		{
+			name: "expired policy is denied",
+			policy: BucketAccessPolicy{
+				AllowedActions: []string{"s3:GetObject"},
+				Expiry:         past,
+			},
+			action: "s3:GetObject",
+			want:   false,
+		},
+		{
+			name: "unexpired policy is allowed",
+			policy: BucketAccessPolicy{
+				AllowedActions: []string{"s3:GetObject"},
+				Expiry:         future,
+			},
+			action: "s3:GetObject",
+			want:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.policy.Allows(tc.action, tc.resource, tc.sourceIP); got != tc.want {
+				t.Errorf("Allows() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCredentialRotationStateGraceValid(t *testing.T) {
+	prev := auth.Credentials{AccessKey: "AKPREV"}
+
+	testCases := []struct {
+		name string
+		r    *credentialRotationState
+		want bool
+	}{
+		{name: "nil state", r: nil, want: false},
+		{name: "no previous credential", r: &credentialRotationState{GraceExpiry: time.Now().Add(time.Hour)}, want: false},
+		{
+			name: "previous credential still within grace window",
+			r:    &credentialRotationState{Previous: &prev, GraceExpiry: time.Now().Add(time.Hour)},
+			want: true,
+		},
+		{
+			name: "previous credential past its grace window",
+			r:    &credentialRotationState{Previous: &prev, GraceExpiry: time.Now().Add(-time.Hour)},
+			want: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.r.graceValid(); got != tc.want {
+				t.Errorf("graceValid() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCredentialRotationStateExpired(t *testing.T) {
+	testCases := []struct {
+		name string
+		r    *credentialRotationState
+		want bool
+	}{
+		{name: "nil state", r: nil, want: false},
+		{name: "no TTL never expires", r: &credentialRotationState{IssuedAt: time.Now().Add(-24 * time.Hour)}, want: false},
+		{
+			name: "issued well within TTL",
+			r:    &credentialRotationState{IssuedAt: time.Now(), TTL: time.Hour},
+			want: false,
+		},
+		{
+			name: "TTL elapsed",
+			r:    &credentialRotationState{IssuedAt: time.Now().Add(-2 * time.Hour), TTL: time.Hour},
+			want: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.r.expired(); got != tc.want {
+				t.Errorf("expired() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDiffServerConfig(t *testing.T) {
+	base := func() *serverConfig {
+		return &serverConfig{
+			Credential: auth.Credentials{AccessKey: "AKOLD"},
+			Region:     "us-east-1",
+			Notify:     &notifier{},
+		}
+	}
+
+	t.Run("nil oldCfg reports no changes", func(t *testing.T) {
+		if events := diffServerConfig(nil, base()); events != nil {
+			t.Errorf("diffServerConfig(nil, ...) = %v, want nil", events)
+		}
+	})
+
+	t.Run("identical configs report no changes", func(t *testing.T) {
+		oldCfg, newCfg := base(), base()
+		if events := diffServerConfig(oldCfg, newCfg); len(events) != 0 {
+			t.Errorf("diffServerConfig() = %v, want no events", events)
+		}
+	})
+
+	t.Run("credential and region changes are both reported", func(t *testing.T) {
+		oldCfg := base()
+		newCfg := base()
+		newCfg.Credential = auth.Credentials{AccessKey: "AKNEW"}
+		newCfg.Region = "us-west-2"
+
+		events := diffServerConfig(oldCfg, newCfg)
+		if len(events) != 2 {
+			t.Fatalf("diffServerConfig() returned %d events, want 2: %v", len(events), events)
+		}
+
+		var sawCredential, sawRegion bool
+		for _, event := range events {
+			switch event.Type {
+			case configChangeCredential:
+				sawCredential = true
+			case configChangeRegion:
+				sawRegion = true
+			}
+		}
+		if !sawCredential || !sawRegion {
+			t.Errorf("diffServerConfig() = %v, want both a credential and a region change", events)
+		}
+	})
+}
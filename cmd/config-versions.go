@@ -0,0 +1,70 @@
+/*
+ * Minio Cloud Storage, (C) 2016, 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"sync"
+
+	"github.com/minio/minio/pkg/auth"
+)
+
+// serverConfigV23 is the v23 server configuration struct, fields are
+// serialized to/from config.json via quick.Save/quick.Load.
+type serverConfigV23 struct {
+	sync.RWMutex
+	Version string `json:"version"`
+
+	// S3 API configuration.
+	Credential auth.Credentials `json:"credential"`
+	Region     string           `json:"region"`
+	Browser    BrowserFlag      `json:"browser"`
+	Domain     string           `json:"domain,omitempty"`
+
+	// Storage classes configuration
+	StorageClass storageClassConfig `json:"storageclass"`
+
+	// Notification queue configuration.
+	Notify *notifier `json:"notify"`
+
+	// Bucket access credentials, keyed by bucket name - lets an external
+	// tool be handed a key scoped to a single bucket instead of the
+	// master credential.
+	Bucket map[string]auth.Credentials `json:"bucket"`
+
+	// BucketPolicies attaches a BucketAccessPolicy to a bucket's
+	// credentials, keyed by bucket name.
+	BucketPolicies map[string]BucketAccessPolicy `json:"bucketPolicies,omitempty"`
+
+	// CredentialRotation tracks the rotation lifecycle of the master
+	// credential: when it was last rotated, and the previous credential
+	// still honored during its grace window.
+	CredentialRotation *credentialRotationState `json:"credentialRotation,omitempty"`
+
+	// BucketCredentialRotation tracks the rotation lifecycle of each
+	// bucket's credential, keyed by bucket name.
+	BucketCredentialRotation map[string]*credentialRotationState `json:"bucketCredentialRotation,omitempty"`
+
+	// CredentialRef, when set, resolves the master credential from an
+	// external secret backend (see CredentialProvider) instead of using
+	// Credential directly.
+	CredentialRef *credentialRef `json:"credentialRef,omitempty"`
+
+	// BucketCredentialRefs resolves a bucket's credential from an
+	// external secret backend instead of the Bucket map, keyed by
+	// bucket name.
+	BucketCredentialRefs map[string]credentialRef `json:"bucketCredentialRefs,omitempty"`
+}
@@ -0,0 +1,120 @@
+/*
+ * Minio Cloud Storage, (C) 2016, 2017 Minio, Inc.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncryptDecryptAESGCMRoundTrip(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+	plaintext := []byte("super secret payload")
+
+	ciphertext, err := encryptAESGCM(key, plaintext)
+	if err != nil {
+		t.Fatalf("encryptAESGCM() error = %v", err)
+	}
+	if bytes.Equal(ciphertext, plaintext) {
+		t.Fatal("encryptAESGCM() returned the plaintext unchanged")
+	}
+
+	decrypted, err := decryptAESGCM(key, ciphertext)
+	if err != nil {
+		t.Fatalf("decryptAESGCM() error = %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("decryptAESGCM() = %q, want %q", decrypted, plaintext)
+	}
+}
+
+func TestDecryptAESGCMWrongKeyFails(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+	wrongKey := bytes.Repeat([]byte{0x24}, 32)
+
+	ciphertext, err := encryptAESGCM(key, []byte("payload"))
+	if err != nil {
+		t.Fatalf("encryptAESGCM() error = %v", err)
+	}
+
+	if _, err := decryptAESGCM(wrongKey, ciphertext); err == nil {
+		t.Error("decryptAESGCM() with the wrong key succeeded, want an error")
+	}
+}
+
+func TestDecryptAESGCMShortCiphertextFails(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+
+	if _, err := decryptAESGCM(key, []byte("too short")); err == nil {
+		t.Error("decryptAESGCM() with a truncated ciphertext succeeded, want an error")
+	}
+}
+
+func TestFileCredentialProviderResolve(t *testing.T) {
+	key := bytes.Repeat([]byte{0x7b}, 32)
+
+	plaintext, err := json.Marshal(struct {
+		AccessKey string `json:"accessKey"`
+		SecretKey string `json:"secretKey"`
+	}{AccessKey: "AKTESTRESOLVE", SecretKey: "secretkeyforresolvetest"})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	ciphertext, err := encryptAESGCM(key, plaintext)
+	if err != nil {
+		t.Fatalf("encryptAESGCM() error = %v", err)
+	}
+
+	dir, err := ioutil.TempDir("", "minio-cred-provider-file-test")
+	if err != nil {
+		t.Fatalf("ioutil.TempDir() error = %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "master.enc")
+	if err = ioutil.WriteFile(path, ciphertext, 0600); err != nil {
+		t.Fatalf("ioutil.WriteFile() error = %v", err)
+	}
+
+	oldKeyEnv := os.Getenv(fileCredentialProviderKeyEnv)
+	os.Setenv(fileCredentialProviderKeyEnv, base64.StdEncoding.EncodeToString(key))
+	defer os.Setenv(fileCredentialProviderKeyEnv, oldKeyEnv)
+
+	cred, err := (fileCredentialProvider{}).Resolve(path)
+	if err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if cred.AccessKey != "AKTESTRESOLVE" {
+		t.Errorf("Resolve() AccessKey = %q, want %q", cred.AccessKey, "AKTESTRESOLVE")
+	}
+}
+
+func TestFileCredentialProviderResolveMissingKeyEnv(t *testing.T) {
+	oldKeyEnv := os.Getenv(fileCredentialProviderKeyEnv)
+	os.Unsetenv(fileCredentialProviderKeyEnv)
+	defer os.Setenv(fileCredentialProviderKeyEnv, oldKeyEnv)
+
+	if _, err := (fileCredentialProvider{}).Resolve("/nonexistent"); err == nil {
+		t.Error("Resolve() with no key configured succeeded, want an error")
+	}
+}